@@ -0,0 +1,402 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"container/heap"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+// Defaults for the caps enforced by blockRequestScheduler. A single
+// misbehaving peer probing many fork tips shouldn't be able to exhaust our
+// outstanding-request budget and starve catch-up on the preferred chain.
+const (
+	defaultMaxOutstandingRequestsPerPeer = 16
+	defaultMaxOutstandingBlockRequests   = 128
+
+	// backoff parameters for requests that time out. Doubles per consecutive
+	// failure for a given block, capped at maxRequestBackoff, and we stop
+	// automatically retrying after maxRequestBackoffRetries.
+	baseRequestBackoff       = 500 * time.Millisecond
+	maxRequestBackoff        = 30 * time.Second
+	maxRequestBackoffRetries = 8
+)
+
+// dispatchFunc actually puts a Get on the wire for a scheduled request.
+type dispatchFunc func(vdr ids.ShortID, requestID uint32, blkID ids.ID)
+
+// pickValidatorFunc samples a validator other than [exclude] to retry a
+// timed-out request against. Returns false if no alternate is available.
+type pickValidatorFunc func(exclude ids.ShortID) (ids.ShortID, bool)
+
+// pendingRequest is an entry waiting in the scheduler's priority queue
+// because issuing it immediately would exceed a cap.
+type pendingRequest struct {
+	blkID ids.ID
+	vdr   ids.ShortID
+	// distance is the number of hops from the block we actually want
+	// (typically the current preference); lower is more urgent.
+	distance int
+	// waiters is the number of times this block has been requested, which
+	// approximates how many blocked/pending chains are transitively waiting
+	// on it; higher is more urgent.
+	waiters int
+	arrival time.Time
+}
+
+// requestHeap orders pendingRequests by distance, then by how many waiters
+// are blocked on the block, then by arrival order (FIFO), so speculative
+// fork fetches queue behind anything on the path to the preferred tip.
+type requestHeap []*pendingRequest
+
+func (h requestHeap) Len() int { return len(h) }
+func (h requestHeap) Less(i, j int) bool {
+	if h[i].distance != h[j].distance {
+		return h[i].distance < h[j].distance
+	}
+	if h[i].waiters != h[j].waiters {
+		return h[i].waiters > h[j].waiters
+	}
+	return h[i].arrival.Before(h[j].arrival)
+}
+func (h requestHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *requestHeap) Push(x interface{}) { *h = append(*h, x.(*pendingRequest)) }
+func (h *requestHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// blockMeta tracks, per block, state that needs to survive a single
+// active/queued lifetime so it can inform a retry after a timeout: the best
+// (lowest) distance seen so far and how many consecutive timeouts we've hit.
+type blockMeta struct {
+	distance int
+	failures int
+}
+
+// backoffEntry is a timed-out request waiting out its backoff before being
+// re-scheduled against [vdr] (a different validator when one was available).
+type backoffEntry struct {
+	blkID   ids.ID
+	vdr     ids.ShortID
+	readyAt time.Time
+}
+
+// blockRequestScheduler bounds the number of outstanding block requests,
+// globally and per peer, and prioritizes requests for blocks closer to the
+// current preference - and with more chains blocked on them - over
+// speculative ancestry fetches for forks. Requests that would exceed a cap
+// are queued and dispatched as budget frees up. Requests that time out are
+// retried with exponential backoff, against a different validator when one
+// is available.
+type blockRequestScheduler struct {
+	maxPerPeer    int
+	maxTotal      int
+	nextRequestID func() uint32
+	dispatch      dispatchFunc
+
+	// active mirrors the requests that have actually been sent on the wire.
+	active common.Requests
+	// activeVdr tracks which validator an in-flight request for a block was
+	// sent to, since common.Requests only indexes the other direction
+	// ((vdr, requestID) -> blkID) and RemoveAny only has the blkID.
+	activeVdr map[[32]byte]ids.ShortID
+	// perPeer tracks in-flight requests per validator, for the per-peer cap.
+	perPeer map[[20]byte]int
+
+	queue      requestHeap
+	queued     ids.Set
+	queueIndex map[[32]byte]*pendingRequest
+
+	meta map[[32]byte]*blockMeta
+
+	backingOff    []*backoffEntry
+	backingOffSet ids.Set
+
+	numRejectedForCap uint64
+	numTimeouts       uint64
+	numReissued       uint64
+	numAbandoned      uint64
+}
+
+// newBlockRequestScheduler returns a scheduler that issues requests via
+// [dispatch], assigning request IDs with [nextRequestID]. A maxPerPeer or
+// maxTotal of 0 falls back to the package defaults.
+func newBlockRequestScheduler(maxPerPeer, maxTotal int, nextRequestID func() uint32, dispatch dispatchFunc) *blockRequestScheduler {
+	if maxPerPeer <= 0 {
+		maxPerPeer = defaultMaxOutstandingRequestsPerPeer
+	}
+	if maxTotal <= 0 {
+		maxTotal = defaultMaxOutstandingBlockRequests
+	}
+	return &blockRequestScheduler{
+		maxPerPeer:    maxPerPeer,
+		maxTotal:      maxTotal,
+		nextRequestID: nextRequestID,
+		dispatch:      dispatch,
+		perPeer:       make(map[[20]byte]int),
+		activeVdr:     make(map[[32]byte]ids.ShortID),
+		queueIndex:    make(map[[32]byte]*pendingRequest),
+		meta:          make(map[[32]byte]*blockMeta),
+	}
+}
+
+// Contains returns true if a request for [blkID] is outstanding, queued
+// behind a cap, or waiting out a backoff after a timeout.
+func (s *blockRequestScheduler) Contains(blkID ids.ID) bool {
+	return s.active.Contains(blkID) || s.queued.Contains(blkID) || s.backingOffSet.Contains(blkID)
+}
+
+// Schedule requests block [blkID] from [vdr], dispatching immediately if
+// budget allows and queueing it at priority [distance] otherwise. [distance]
+// should be the number of missing ancestors between this request and the
+// block we actually want issued; 0 means the block was asked for directly.
+// Calling Schedule again for a [blkID] that's already tracked sharpens its
+// distance and, if it's still queued, bumps its waiter count, rather than
+// issuing a second request.
+func (s *blockRequestScheduler) Schedule(vdr ids.ShortID, blkID ids.ID, distance int) {
+	key := blkID.Key()
+	m, ok := s.meta[key]
+	if !ok {
+		m = &blockMeta{distance: distance}
+		s.meta[key] = m
+	} else if distance < m.distance {
+		m.distance = distance
+	}
+
+	if p, ok := s.queueIndex[key]; ok {
+		p.waiters++
+		p.distance = m.distance
+		s.fixQueue(p)
+		return
+	}
+	if s.Contains(blkID) {
+		return
+	}
+	if s.canIssue(vdr) {
+		s.issue(vdr, blkID)
+		return
+	}
+	p := &pendingRequest{blkID: blkID, vdr: vdr, distance: m.distance, waiters: 1, arrival: time.Now()}
+	heap.Push(&s.queue, p)
+	s.queued.Add(blkID)
+	s.queueIndex[key] = p
+	s.numRejectedForCap++
+}
+
+// Remove clears the outstanding request keyed by (vdr, requestID), ends any
+// retry tracking for its block, and drains any queued requests that now fit
+// under the freed-up budget. Returns the requested block ID, if there was
+// one.
+func (s *blockRequestScheduler) Remove(vdr ids.ShortID, requestID uint32) (ids.ID, bool) {
+	blkID, ok := s.active.Remove(vdr, requestID)
+	if !ok {
+		return ids.ID{}, false
+	}
+	delete(s.activeVdr, blkID.Key())
+	s.release(vdr)
+	delete(s.meta, blkID.Key())
+	s.drain()
+	return blkID, true
+}
+
+// Timeout handles a request keyed by (vdr, requestID) expiring without a
+// response. If the block hasn't already exhausted its retry budget, the
+// request is re-scheduled after an exponential backoff against an alternate
+// validator from [pickValidator], or against [vdr] again if none is
+// available, and retrying is reported via the third return value. Otherwise
+// the caller should treat the block as abandoned.
+func (s *blockRequestScheduler) Timeout(vdr ids.ShortID, requestID uint32, pickValidator pickValidatorFunc) (blkID ids.ID, found, retrying bool) {
+	blkID, ok := s.active.Remove(vdr, requestID)
+	if !ok {
+		return ids.ID{}, false, false
+	}
+	delete(s.activeVdr, blkID.Key())
+	s.release(vdr)
+	s.numTimeouts++
+
+	key := blkID.Key()
+	m, ok := s.meta[key]
+	if !ok {
+		m = &blockMeta{}
+		s.meta[key] = m
+	}
+	m.failures++
+
+	if m.failures > maxRequestBackoffRetries {
+		delete(s.meta, key)
+		s.numAbandoned++
+		s.drain()
+		return blkID, true, false
+	}
+
+	newVdr, ok := pickValidator(vdr)
+	if !ok {
+		newVdr = vdr // no alternate available; retry the same validator after backing off
+	}
+
+	s.backingOff = append(s.backingOff, &backoffEntry{
+		blkID:   blkID,
+		vdr:     newVdr,
+		readyAt: time.Now().Add(backoffDelay(m.failures)),
+	})
+	s.backingOffSet.Add(blkID)
+	s.numReissued++
+	s.drain()
+	return blkID, true, true
+}
+
+// Tick re-schedules any backed-off requests whose delay has elapsed. It
+// should be called periodically, e.g. alongside the engine's Gossip tick.
+func (s *blockRequestScheduler) Tick(now time.Time) {
+	if len(s.backingOff) == 0 {
+		return
+	}
+	ready := s.backingOff[:0]
+	for _, e := range s.backingOff {
+		if now.Before(e.readyAt) {
+			ready = append(ready, e)
+			continue
+		}
+		s.backingOffSet.Remove(e.blkID)
+		s.Schedule(e.vdr, e.blkID, s.meta[e.blkID.Key()].distance)
+	}
+	s.backingOff = ready
+}
+
+// RemoveAny drops any outstanding, queued, or backing-off request for
+// [blkID]. This is the common-path cleanup used whenever a block gets
+// issued via some route other than one of our own dispatched requests (e.g.
+// it arrived unsolicited, or another request for it already resolved it),
+// so it must release the per-peer slot and drain the queue exactly like
+// Remove does - otherwise the serving peer's perPeer counter leaks and that
+// peer is eventually never asked again.
+func (s *blockRequestScheduler) RemoveAny(blkID ids.ID) {
+	key := blkID.Key()
+	if vdr, ok := s.activeVdr[key]; ok {
+		delete(s.activeVdr, key)
+		s.release(vdr)
+	}
+	s.active.RemoveAny(blkID)
+	delete(s.meta, key)
+
+	if s.backingOffSet.Contains(blkID) {
+		s.backingOffSet.Remove(blkID)
+		filtered := s.backingOff[:0]
+		for _, e := range s.backingOff {
+			if !e.blkID.Equals(blkID) {
+				filtered = append(filtered, e)
+			}
+		}
+		s.backingOff = filtered
+	}
+
+	if s.queued.Contains(blkID) {
+		s.queued.Remove(blkID)
+		delete(s.queueIndex, blkID.Key())
+		filtered := s.queue[:0]
+		for _, item := range s.queue {
+			if !item.blkID.Equals(blkID) {
+				filtered = append(filtered, item)
+			}
+		}
+		s.queue = filtered
+		heap.Init(&s.queue)
+	}
+
+	s.drain()
+}
+
+// Len returns the total number of requests this scheduler is tracking:
+// in-flight, queued, or backing off after a timeout.
+func (s *blockRequestScheduler) Len() int { return s.active.Len() + len(s.queue) + len(s.backingOff) }
+
+// NumInFlight returns the number of requests currently in flight on the wire.
+func (s *blockRequestScheduler) NumInFlight() int { return s.active.Len() }
+
+// NumQueued returns the number of requests currently held back by a cap.
+func (s *blockRequestScheduler) NumQueued() int { return len(s.queue) }
+
+// NumBackingOff returns the number of timed-out requests waiting out a
+// backoff before being retried.
+func (s *blockRequestScheduler) NumBackingOff() int { return len(s.backingOff) }
+
+// NumInFlightFor returns the number of in-flight requests issued to [vdr].
+func (s *blockRequestScheduler) NumInFlightFor(vdr ids.ShortID) int { return s.perPeer[vdr.Key()] }
+
+func (s *blockRequestScheduler) canIssue(vdr ids.ShortID) bool {
+	if s.active.Len() >= s.maxTotal {
+		return false
+	}
+	return s.perPeer[vdr.Key()] < s.maxPerPeer
+}
+
+func (s *blockRequestScheduler) issue(vdr ids.ShortID, blkID ids.ID) {
+	requestID := s.nextRequestID()
+	s.active.Add(vdr, requestID, blkID)
+	s.activeVdr[blkID.Key()] = vdr
+	s.perPeer[vdr.Key()]++
+	s.dispatch(vdr, requestID, blkID)
+}
+
+func (s *blockRequestScheduler) release(vdr ids.ShortID) {
+	key := vdr.Key()
+	if n := s.perPeer[key]; n <= 1 {
+		delete(s.perPeer, key)
+	} else {
+		s.perPeer[key] = n - 1
+	}
+}
+
+// drain issues queued requests in priority order until the global cap is
+// hit, skipping over (rather than stopping at) entries whose peer is at its
+// per-peer cap. Otherwise a single peer sitting at maxPerPeer would
+// head-of-line-block every lower-priority entry behind it even though the
+// global budget still has room and other peers have capacity to spare.
+func (s *blockRequestScheduler) drain() {
+	var skipped []*pendingRequest
+	for len(s.queue) > 0 && s.active.Len() < s.maxTotal {
+		next := heap.Pop(&s.queue).(*pendingRequest)
+		if !s.canIssue(next.vdr) {
+			skipped = append(skipped, next)
+			continue
+		}
+		s.queued.Remove(next.blkID)
+		delete(s.queueIndex, next.blkID.Key())
+		s.issue(next.vdr, next.blkID)
+	}
+	for _, p := range skipped {
+		heap.Push(&s.queue, p)
+	}
+}
+
+// fixQueue restores the heap invariant after [p]'s priority changed in place.
+func (s *blockRequestScheduler) fixQueue(p *pendingRequest) {
+	for i, item := range s.queue {
+		if item == p {
+			heap.Fix(&s.queue, i)
+			return
+		}
+	}
+}
+
+// backoffDelay returns the delay before the [failures]th retry of a request,
+// doubling per failure and capped at maxRequestBackoff.
+func backoffDelay(failures int) time.Duration {
+	if failures <= 0 {
+		return baseRequestBackoff
+	}
+	delay := baseRequestBackoff << uint(failures-1)
+	if delay <= 0 || delay > maxRequestBackoff {
+		return maxRequestBackoff
+	}
+	return delay
+}
@@ -0,0 +1,154 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func testID(b byte) ids.ID {
+	var hash [32]byte
+	hash[0] = b
+	return ids.NewID(hash)
+}
+
+func testShortID(b byte) ids.ShortID {
+	var hash [20]byte
+	hash[0] = b
+	return ids.NewShortID(hash)
+}
+
+// TestBlockRequestSchedulerDrainSkipsPerPeerCappedEntries reproduces the
+// scenario where a single peer sitting at its per-peer cap used to
+// head-of-line-block every lower-priority queued entry behind it, even
+// though the global budget had room and another peer had capacity to
+// spare. drain() should skip the capped entry and dispatch the one behind
+// it instead.
+func TestBlockRequestSchedulerDrainSkipsPerPeerCappedEntries(t *testing.T) {
+	var dispatched []ids.ID
+	nextReqID := uint32(0)
+	s := newBlockRequestScheduler(1, 2, func() uint32 {
+		nextReqID++
+		return nextReqID
+	}, func(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
+		dispatched = append(dispatched, blkID)
+	})
+
+	vdrA, vdrB, vdrC := testShortID(1), testShortID(2), testShortID(3)
+	blk1, blk2, blk3, blk4 := testID(1), testID(2), testID(3), testID(4)
+
+	s.Schedule(vdrA, blk1, 0) // dispatches immediately
+	s.Schedule(vdrA, blk2, 0) // vdrA is at its per-peer cap: queued
+	s.Schedule(vdrB, blk3, 5) // dispatches immediately (global cap now hit)
+	s.Schedule(vdrC, blk4, 1) // global cap hit: queued behind blk2
+
+	if s.NumQueued() != 2 {
+		t.Fatalf("expected 2 queued requests, got %d", s.NumQueued())
+	}
+
+	// Free up global budget, but not vdrA's per-peer slot.
+	if _, ok := s.Remove(vdrB, 2); !ok {
+		t.Fatalf("expected to remove vdrB's outstanding request")
+	}
+
+	foundBlk4 := false
+	for _, blkID := range dispatched {
+		if blkID.Equals(blk4) {
+			foundBlk4 = true
+		}
+	}
+	if !foundBlk4 {
+		t.Fatalf("expected blk4 to be dispatched to vdrC despite blk2 (vdrA, per-peer capped) being ahead of it in the queue")
+	}
+	if s.NumQueued() != 1 {
+		t.Fatalf("expected blk2 to remain queued behind vdrA's cap, got %d queued", s.NumQueued())
+	}
+}
+
+// TestBlockRequestSchedulerRemoveAnyReleasesPerPeerSlot reproduces the
+// starvation bug where RemoveAny - the cleanup path issue() uses for every
+// block that gets issued - dropped the active entry without releasing the
+// serving peer's per-peer slot or draining the queue. Without the fix, a
+// peer that serves maxPerPeer blocks via RemoveAny is never asked again.
+func TestBlockRequestSchedulerRemoveAnyReleasesPerPeerSlot(t *testing.T) {
+	var dispatched []ids.ID
+	nextReqID := uint32(0)
+	s := newBlockRequestScheduler(1, 128, func() uint32 {
+		nextReqID++
+		return nextReqID
+	}, func(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
+		dispatched = append(dispatched, blkID)
+	})
+
+	vdr := testShortID(1)
+	blk1, blk2 := testID(1), testID(2)
+
+	s.Schedule(vdr, blk1, 0) // dispatches immediately, vdr now at its per-peer cap of 1
+	s.RemoveAny(blk1)        // simulates issue()'s cleanup path for a resolved block
+
+	if n := s.NumInFlightFor(vdr); n != 0 {
+		t.Fatalf("expected RemoveAny to release vdr's per-peer slot, got %d still in flight", n)
+	}
+
+	s.Schedule(vdr, blk2, 0)
+	if !s.Contains(blk2) || s.NumInFlight() != 1 {
+		t.Fatalf("expected vdr to be usable again after RemoveAny freed its slot")
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	if d := backoffDelay(0); d != baseRequestBackoff {
+		t.Fatalf("expected base backoff for failures=0, got %s", d)
+	}
+	if d := backoffDelay(1); d != baseRequestBackoff {
+		t.Fatalf("expected base backoff for the first failure, got %s", d)
+	}
+	if d := backoffDelay(2); d != 2*baseRequestBackoff {
+		t.Fatalf("expected backoff to double on the second failure, got %s", d)
+	}
+	if d := backoffDelay(100); d != maxRequestBackoff {
+		t.Fatalf("expected backoff to be capped at maxRequestBackoff, got %s", d)
+	}
+}
+
+func TestBlockRequestSchedulerTick(t *testing.T) {
+	var dispatched []ids.ID
+	nextReqID := uint32(0)
+	s := newBlockRequestScheduler(defaultMaxOutstandingRequestsPerPeer, defaultMaxOutstandingBlockRequests, func() uint32 {
+		nextReqID++
+		return nextReqID
+	}, func(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
+		dispatched = append(dispatched, blkID)
+	})
+
+	vdr := testShortID(1)
+	blk := testID(1)
+	s.Schedule(vdr, blk, 0)
+	if len(dispatched) != 1 {
+		t.Fatalf("expected the initial request to dispatch immediately")
+	}
+
+	blkID, _, retrying := s.Timeout(vdr, 1, func(exclude ids.ShortID) (ids.ShortID, bool) { return ids.ShortID{}, false })
+	if !retrying || !blkID.Equals(blk) {
+		t.Fatalf("expected the request to be retried against the same validator after backing off")
+	}
+	if s.NumBackingOff() != 1 {
+		t.Fatalf("expected 1 backing-off request, got %d", s.NumBackingOff())
+	}
+
+	// Ticking before the backoff elapses shouldn't re-dispatch.
+	s.Tick(time.Time{})
+	if len(dispatched) != 1 {
+		t.Fatalf("expected no re-dispatch before the backoff elapses")
+	}
+
+	// Ticking well past the backoff should.
+	s.Tick(time.Now().Add(maxRequestBackoff))
+	if len(dispatched) != 2 {
+		t.Fatalf("expected the request to be re-dispatched once the backoff elapsed")
+	}
+}
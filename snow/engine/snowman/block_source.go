@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import "github.com/ava-labs/gecko/snow/consensus/snowman"
+
+// BlockSource records how this engine received a block, so operators
+// can tell whether a chain stall traces back to gossip drops, failing pull
+// responses, or something else, and so the engine can apply different
+// retry/poll policies per source.
+type BlockSource int
+
+const (
+	// Gossiped means the block arrived unsolicited, via Gossip or a
+	// PushQuery.
+	Gossiped BlockSource = iota
+	// PullReplied means the block arrived in response to a Get or
+	// GetAncestors we sent.
+	PullReplied
+	// LocallyBuilt means this node's own VM produced the block.
+	LocallyBuilt
+	// Bootstrap means the block was observed while catching up, before
+	// consensus was initialized.
+	Bootstrap
+)
+
+func (s BlockSource) String() string {
+	switch s {
+	case Gossiped:
+		return "gossiped"
+	case PullReplied:
+		return "pull_replied"
+	case LocallyBuilt:
+		return "locally_built"
+	case Bootstrap:
+		return "bootstrap"
+	default:
+		return "unknown"
+	}
+}
+
+// trackedBlock pairs a block pinned in memory with the source it was pinned
+// with. deliver carries the source into droppedCache when it drops a block.
+// Put, PushQuery and MultiPut record the source of the message they handle,
+// replacing any source recorded earlier; they do not read the source back
+// from droppedCache.
+type trackedBlock struct {
+	blk    snowman.Block
+	source BlockSource
+}
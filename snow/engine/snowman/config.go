@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/engine/snowman/bootstrap"
+	"github.com/ava-labs/gecko/vms/components/missing"
+)
+
+// Config collects everything Transitive.Initialize needs to stand up a
+// consensus engine for a single chain: the bootstrapper config it forwards
+// unchanged, the snowman-specific consensus parameters, and the optional
+// hooks and policies this engine supports.
+type Config struct {
+	bootstrap.Config
+
+	Params    snowball.Parameters
+	Consensus snowman.Consensus
+
+	// ConsensusTracer receives a structured record of every consensus state
+	// transition this engine makes. Nil disables tracing.
+	ConsensusTracer ConsensusTracer
+
+	// StateSyncEnabled opts this chain into the state-sync fast path when
+	// its VM also implements StateSyncableVM and reports support for it.
+	StateSyncEnabled bool
+
+	// EquivocationReporter is notified when a validator casts conflicting
+	// votes across concurrently outstanding polls. Nil disables reporting
+	// (detection itself still happens).
+	EquivocationReporter EquivocationReporter
+
+	// FilteredVoteReporter is notified whenever a vote is dropped by this
+	// engine's vote filter. Nil disables reporting (filtering itself still
+	// happens).
+	FilteredVoteReporter FilteredVoteReporter
+
+	// MaxOutstandingRequestsPerPeer and MaxOutstandingBlockRequests cap the
+	// blockRequestScheduler's per-peer and global outstanding Get budgets.
+	// Zero falls back to the scheduler's package defaults.
+	MaxOutstandingRequestsPerPeer int
+	MaxOutstandingBlockRequests   int
+
+	// MissingPolicy governs how a missing.Block sentinel reacts if
+	// Accept/Reject/Verify is ever called on it for this chain. Defaults to
+	// missing.PolicyError.
+	MissingPolicy missing.Policy
+}
@@ -0,0 +1,71 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import "github.com/ava-labs/gecko/ids"
+
+// maxTrackedPollsPerValidator bounds the memory an equivocationTracker uses
+// per validator: once a validator has this many outstanding polls tracked,
+// the oldest is evicted to make room for the newest.
+const maxTrackedPollsPerValidator = 8
+
+// EquivocationReporter is notified when a validator is caught voting for
+// conflicting blocks across concurrent polls. It's a hook rather than a
+// hard failure: the engine keeps running either way, but operators and
+// eventual slashing logic need to know this happened.
+type EquivocationReporter interface {
+	ReportEquivocation(vdr ids.ShortID, blkA, blkB ids.ID)
+}
+
+// pollVotes remembers, for a single validator, which block it voted for in
+// each of its most recent outstanding polls.
+type pollVotes struct {
+	order []uint32 // requestIDs in arrival order, oldest first
+	byReq map[uint32]ids.ID
+}
+
+// equivocationTracker records each validator's vote across concurrently
+// outstanding polls so that conflicting votes - for blocks that aren't on
+// the same chain - can be caught as soon as they're cast.
+type equivocationTracker struct {
+	votes map[[20]byte]*pollVotes
+}
+
+func newEquivocationTracker() *equivocationTracker {
+	return &equivocationTracker{votes: make(map[[20]byte]*pollVotes)}
+}
+
+// Record stores [vdr]'s vote for [blkID] in poll [requestID] and returns the
+// blocks [vdr] voted for in any of its other still-tracked polls. The
+// caller is responsible for deciding whether any of those votes actually
+// conflict with [blkID] (e.g. via an ancestry check), since this tracker
+// doesn't have visibility into the chain.
+func (e *equivocationTracker) Record(vdr ids.ShortID, requestID uint32, blkID ids.ID) []ids.ID {
+	key := vdr.Key()
+	pv, ok := e.votes[key]
+	if !ok {
+		pv = &pollVotes{byReq: make(map[uint32]ids.ID)}
+		e.votes[key] = pv
+	}
+
+	others := make([]ids.ID, 0, len(pv.byReq))
+	for otherReq, otherBlk := range pv.byReq {
+		if otherReq != requestID {
+			others = append(others, otherBlk)
+		}
+	}
+
+	if _, exists := pv.byReq[requestID]; !exists {
+		pv.order = append(pv.order, requestID)
+	}
+	pv.byReq[requestID] = blkID
+
+	for len(pv.order) > maxTrackedPollsPerValidator {
+		oldest := pv.order[0]
+		pv.order = pv.order[1:]
+		delete(pv.byReq, oldest)
+	}
+
+	return others
+}
@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import "testing"
+
+func TestEquivocationTrackerRecordReturnsOtherOutstandingVotes(t *testing.T) {
+	e := newEquivocationTracker()
+	vdr := testShortID(1)
+	blkA, blkB, blkC := testID(1), testID(2), testID(3)
+
+	if others := e.Record(vdr, 1, blkA); len(others) != 0 {
+		t.Fatalf("expected no prior votes for a validator's first poll, got %d", len(others))
+	}
+
+	others := e.Record(vdr, 2, blkB)
+	if len(others) != 1 || !others[0].Equals(blkA) {
+		t.Fatalf("expected the validator's vote in poll 1 (%s) to be surfaced, got %v", blkA, others)
+	}
+
+	others = e.Record(vdr, 3, blkC)
+	if len(others) != 2 {
+		t.Fatalf("expected both prior votes to be surfaced, got %v", others)
+	}
+
+	// Re-recording the same (vdr, requestID) shouldn't duplicate the entry
+	// or surface the vote against itself.
+	others = e.Record(vdr, 3, blkC)
+	if len(others) != 2 {
+		t.Fatalf("expected re-recording the same poll to still surface exactly the other 2 votes, got %v", others)
+	}
+}
+
+func TestEquivocationTrackerEvictsOldestPollPerValidator(t *testing.T) {
+	e := newEquivocationTracker()
+	vdr := testShortID(1)
+
+	for i := uint32(0); i < maxTrackedPollsPerValidator+2; i++ {
+		e.Record(vdr, i, testID(byte(i)))
+	}
+
+	others := e.Record(vdr, maxTrackedPollsPerValidator+2, testID(255))
+	if len(others) != maxTrackedPollsPerValidator {
+		t.Fatalf("expected tracking to be bounded to %d polls, got %d", maxTrackedPollsPerValidator, len(others))
+	}
+}
+
+func TestEquivocationTrackerTracksValidatorsIndependently(t *testing.T) {
+	e := newEquivocationTracker()
+	vdrA, vdrB := testShortID(1), testShortID(2)
+	blk := testID(1)
+
+	e.Record(vdrA, 1, blk)
+	if others := e.Record(vdrB, 1, blk); len(others) != 0 {
+		t.Fatalf("expected a different validator's vote to not leak into vdrB's tracked polls, got %v", others)
+	}
+}
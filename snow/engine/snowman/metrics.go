@@ -0,0 +1,70 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// metrics collects the Prometheus metrics this engine exports, and is
+// embedded directly into Transitive so its fields can be set without an
+// extra layer of indirection.
+type metrics struct {
+	numRequests,
+	numBlocked,
+	numProcessing prometheus.Gauge
+
+	// blocksIssuedBySource counts, per BlockSource label, how many blocks
+	// this engine has issued to consensus.
+	blocksIssuedBySource *prometheus.CounterVec
+
+	// requestsByState breaks numRequests down by where each outstanding
+	// request currently sits: queued behind a cap, in flight on the wire,
+	// or backing off after a timeout.
+	requestsByState *prometheus.GaugeVec
+}
+
+// Initialize registers this engine's metrics under [namespace] with [reg].
+func (m *metrics) Initialize(namespace string, reg prometheus.Registerer) error {
+	errs := wrappers.Errs{}
+
+	m.numRequests = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "requests",
+		Help:      "Number of outstanding block requests",
+	})
+	errs.Add(reg.Register(m.numRequests))
+
+	m.numBlocked = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "blocked",
+		Help:      "Number of blocks queued to be added to consensus",
+	})
+	errs.Add(reg.Register(m.numBlocked))
+
+	m.numProcessing = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "processing",
+		Help:      "Number of blocks pinned in memory awaiting a decision",
+	})
+	errs.Add(reg.Register(m.numProcessing))
+
+	m.blocksIssuedBySource = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_issued",
+		Help:      "Number of blocks issued to consensus, by the source recorded when they were pinned",
+	}, []string{"source"})
+	errs.Add(reg.Register(m.blocksIssuedBySource))
+
+	m.requestsByState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "requests_by_state",
+		Help:      "Number of outstanding block requests, by whether they're queued, in flight, or backing off after a timeout",
+	}, []string{"state"})
+	errs.Add(reg.Register(m.requestsByState))
+
+	return errs.Err
+}
@@ -0,0 +1,49 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import "github.com/ava-labs/gecko/ids"
+
+// StateSyncableVM may be implemented by a VM that can bootstrap by fetching
+// a recent state summary from the network instead of replaying every block
+// since genesis. A VM that doesn't implement this interface (or returns
+// false from StateSyncEnabled) always goes through full bootstrap.
+type StateSyncableVM interface {
+	// StateSyncEnabled returns true if the VM is willing to state sync right now.
+	StateSyncEnabled() bool
+
+	// GetOngoingStateSyncSummary returns the summary this VM is in the
+	// middle of applying, if it was interrupted by a restart, so that sync
+	// can resume rather than starting over.
+	GetOngoingStateSyncSummary() (StateSummary, error)
+
+	// GetLastStateSummary returns the most recent state summary this VM can
+	// produce.
+	GetLastStateSummary() (StateSummary, error)
+
+	// ParseStateSummary parses a state summary received from the network.
+	ParseStateSummary(summaryBytes []byte) (StateSummary, error)
+
+	// GetStateSummary returns the state summary for the block at [height],
+	// if this VM has one available.
+	GetStateSummary(height uint64) (StateSummary, error)
+}
+
+// StateSummary identifies a point a VM can fast-forward its state to.
+type StateSummary interface {
+	// ID is the identifier of this summary, as agreed on via the snowball
+	// poll run over competing summaries.
+	ID() ids.ID
+
+	// Height is the height of the block this summary fast-forwards to.
+	Height() uint64
+
+	// Bytes is the serialized form of this summary, as sent over the wire.
+	Bytes() []byte
+
+	// Accept instructs the VM to fetch and apply the state this summary
+	// describes. Returns once the VM's state (not necessarily the block
+	// history) reflects this summary.
+	Accept() error
+}
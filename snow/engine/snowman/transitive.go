@@ -33,6 +33,16 @@ const (
 
 	// Max size of cache of dropped blocks
 	droppedCacheSize = 1000
+
+	// If issueFrom stalls on the same missing ancestor more than this many
+	// times in a row, request its ancestry with GetAncestors instead of
+	// sending another single Get for it.
+	ancestorsReqThreshold = 1
+
+	// missingBlockTTL bounds how long a missing.Block sentinel is allowed to
+	// sit unresolved before issueWithAncestors gives up on it instead of
+	// continuing to wait on its outstanding request.
+	missingBlockTTL = 2 * time.Minute
 )
 
 // Transitive implements the Engine interface by attempting to fetch all
@@ -47,8 +57,31 @@ type Transitive struct {
 	// track outstanding preference requests
 	polls poll.Set
 
-	// blocks that have we have sent get requests for but haven't yet received
-	blkReqs common.Requests
+	// blocks that have we have sent get requests for but haven't yet received.
+	// Backed by a priority scheduler so that requests for ancestors of our
+	// current preference are serviced ahead of speculative fork fetches, and
+	// so a single peer can't exhaust our outstanding-request budget.
+	blkReqs *blockRequestScheduler
+
+	// blocks that we've requested via GetAncestors but haven't yet received
+	// a MultiPut for
+	ancReqs common.Requests
+
+	// ancestorMisses counts, per missing ancestor block ID, how many times
+	// in a row issueFrom has hit that exact gap while walking a branch back
+	// to consensus. A missing ancestor's real parent isn't known locally, so
+	// a single issueFrom call can't see how deep the gap goes; this persists
+	// across calls so a gap that keeps stalling issuance escalates from a
+	// single Get to a GetAncestors instead of retrying the same Get forever.
+	ancestorMisses map[[32]byte]int
+
+	// missingBlocks holds the persistent missing.Block sentinel for each
+	// ancestor issueWithAncestors has hit but hasn't resolved yet, keyed by
+	// block ID. Keeping it persistent (instead of a fresh sentinel on every
+	// walk) lets Attempts/FirstSeen actually accumulate so the engine can
+	// tell a block that was just requested apart from one that's been
+	// missing long enough to give up on.
+	missingBlocks map[[32]byte]*missing.Block
 
 	// blocks that are queued to be issued to consensus once missing dependencies are fetched
 	pending ids.Set
@@ -61,11 +94,11 @@ type Transitive struct {
 	errs wrappers.Errs
 
 	// Key: ID of a processing block
-	// Value: The block
+	// Value: The block, tagged with the source of the message that last pinned it
 	// Invariant: Every block in this map is processing
 	// If a block is dropped, it will be removed from this map.
 	// However, it may be re-added later.
-	processing map[[32]byte]snowman.Block
+	processing map[[32]byte]trackedBlock
 
 	// Cache of decided block IDs.
 	// Key: Block ID
@@ -76,6 +109,37 @@ type Transitive struct {
 	// We keep this so that if we drop a block and receive a query for it,
 	// we don't need to fetch the block again
 	droppedCache cache.LRU
+
+	// tracer receives a structured record of every consensus state
+	// transition this engine makes. Defaults to a no-op.
+	tracer ConsensusTracer
+
+	// missingResolver governs how a missing.Block sentinel reacts if
+	// Accept/Reject/Verify is ever called on it. It's owned by this engine
+	// instance rather than set package-wide, since a single avalanchego
+	// process runs many chains and they must not share a panic/log policy.
+	missingResolver *missing.Resolver
+
+	// stateSyncVM is set iff the VM this engine is running opts into state
+	// sync. Initialize uses it only to resume a summary the VM was already
+	// applying (see tryStateSync); this engine runs no poll over competing
+	// summaries and streams no state itself.
+	stateSyncVM StateSyncableVM
+
+	// equivocations tracks each validator's votes across concurrently
+	// outstanding polls so double-voting can be caught and surfaced via
+	// equivocationReporter.
+	equivocations      *equivocationTracker
+	equivocationReporter EquivocationReporter
+	numEquivocations   uint64
+
+	// votes collapses each validator to at most one counted vote per poll,
+	// and drops votes for blocks we can't resolve locally, before a vote
+	// ever reaches the poll itself. Surfaced via filteredVoteReporter.
+	votes                *voteFilter
+	filteredVoteReporter FilteredVoteReporter
+	numFilteredVotes     uint64
+
 }
 
 // Initialize implements the Engine interface
@@ -91,14 +155,53 @@ func (t *Transitive) Initialize(config Config) error {
 		config.Params.Namespace,
 		config.Params.Metrics,
 	)
-	t.processing = map[[32]byte]snowman.Block{}
+	t.processing = map[[32]byte]trackedBlock{}
+	t.ancestorMisses = map[[32]byte]int{}
+	t.missingBlocks = map[[32]byte]*missing.Block{}
 	t.decidedCache = cache.LRU{Size: decidedCacheSize}
 	t.droppedCache = cache.LRU{Size: droppedCacheSize}
+	t.blkReqs = newBlockRequestScheduler(
+		config.MaxOutstandingRequestsPerPeer,
+		config.MaxOutstandingBlockRequests,
+		func() uint32 {
+			t.RequestID++
+			return t.RequestID
+		},
+		t.dispatchGet,
+	)
+
+	t.tracer = config.ConsensusTracer
+	if t.tracer == nil {
+		t.tracer = NewNoOpTracer()
+	}
+
+	t.missingResolver = &missing.Resolver{Policy: config.MissingPolicy, Logger: config.Ctx.Log}
+
+	if vm, ok := config.VM.(StateSyncableVM); ok && config.StateSyncEnabled && vm.StateSyncEnabled() {
+		config.Ctx.Log.Info("VM supports state sync; awaiting a synced summary before falling back to full bootstrap")
+		t.stateSyncVM = vm
+	}
+
+	t.equivocations = newEquivocationTracker()
+	t.equivocationReporter = config.EquivocationReporter
+	t.votes = newVoteFilter()
+	t.filteredVoteReporter = config.FilteredVoteReporter
 
 	if err := t.metrics.Initialize(fmt.Sprintf("%s_engine", config.Params.Namespace), config.Params.Metrics); err != nil {
 		return err
 	}
 
+	if t.stateSyncVM != nil {
+		synced, err := t.tryStateSync()
+		if err != nil {
+			return err
+		}
+		if synced {
+			return nil
+		}
+		config.Ctx.Log.Info("no usable state sync summary available; falling back to full bootstrap")
+	}
+
 	return t.Bootstrapper.Initialize(
 		config.Config,
 		t.finishBootstrapping,
@@ -107,11 +210,56 @@ func (t *Transitive) Initialize(config Config) error {
 	)
 }
 
+// tryStateSync resumes a state sync the VM was already in the middle of
+// applying before a restart interrupted it, so the VM doesn't have to start
+// over. Returns true if such a summary was found and applied, so the caller
+// can skip full bootstrap.
+//
+// This deliberately does not also fall back to GetLastStateSummary for a
+// fresh sync: doing so would fast-forward this node's state to whatever
+// summary the local VM happens to have, without any network agreement that
+// it's actually the right one. A real fresh-start state sync needs sampling
+// K validators and running a snowball poll over competing summary IDs before
+// streaming state from a supermajority peer, which needs new message types
+// on common.Sender (e.g. GetStateSummaryFrontier/StateSummaryFrontier) that
+// don't exist in this tree. Resuming an ongoing sync is safe without that,
+// since the summary being resumed was already the one this node decided to
+// apply before the restart.
+func (t *Transitive) tryStateSync() (bool, error) {
+	summary, err := t.stateSyncVM.GetOngoingStateSyncSummary()
+	if err != nil || summary == nil {
+		return false, nil
+	}
+	if err := t.ApplyStateSyncSummary(summary); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // When bootstrapping is finished, this will be called.
 // This initializes the consensus engine with the last accepted block.
 func (t *Transitive) finishBootstrapping() error {
+	return t.finishBootstrappingFrom(t.VM.LastAccepted())
+}
+
+// ApplyStateSyncSummary is called once a state-sync phase has agreed on
+// [summary] to fast-forward to. It hands the summary to the VM and, once
+// applied, initializes consensus from the resulting last accepted block
+// instead of walking back through full bootstrap.
+func (t *Transitive) ApplyStateSyncSummary(summary StateSummary) error {
+	if err := summary.Accept(); err != nil {
+		t.Ctx.Log.Error("failed to apply state sync summary at height %d: %s", summary.Height(), err)
+		return err
+	}
+	t.Ctx.Log.Info("state sync applied summary at height %d, resuming from %s", summary.Height(), summary.ID())
+	return t.finishBootstrappingFrom(t.VM.LastAccepted())
+}
+
+// finishBootstrappingFrom initializes the consensus engine with
+// [lastAcceptedID] as the last accepted block. It's the shared tail of both
+// full bootstrap and a successful state sync.
+func (t *Transitive) finishBootstrappingFrom(lastAcceptedID ids.ID) error {
 	// initialize consensus to the last accepted blockID
-	lastAcceptedID := t.VM.LastAccepted()
 	params := t.Params
 	params.Namespace = fmt.Sprintf("%s_consensus", params.Namespace)
 	t.Consensus.Initialize(t.Ctx, params, lastAcceptedID)
@@ -147,6 +295,12 @@ func (t *Transitive) finishBootstrapping() error {
 
 // Gossip implements the Engine interface
 func (t *Transitive) Gossip() error {
+	// This is the only periodic hook the router gives the engine, so piggy
+	// back on it to re-issue any block requests that finished backing off
+	// since the last tick.
+	t.blkReqs.Tick(time.Now())
+	t.updateRequestMetrics()
+
 	blkID := t.VM.LastAccepted()
 	blk, err := t.GetBlock(blkID)
 	if err != nil {
@@ -236,8 +390,14 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 		// abandon the request.
 		return t.GetFailed(vdr, requestID)
 	}
+	t.emit(TraceBlockReceived, blkID, blk.Parent(), vdr, requestID, nil)
+	delete(t.missingBlocks, blkID.Key()) // blkID is no longer missing
 	if blk.Status() == choices.Processing { // Pin this block in memory until it's decided or dropped
-		t.processing[blk.ID().Key()] = blk
+		source := PullReplied
+		if requestID == constants.GossipMsgRequestID {
+			source = Gossiped
+		}
+		t.processing[blk.ID().Key()] = trackedBlock{blk: blk, source: source}
 		t.droppedCache.Evict(blkID)
 		t.numProcessing.Set(float64(len(t.processing)))
 	}
@@ -261,17 +421,95 @@ func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32) error {
 
 	// We don't assume that this function is called after a failed Get message.
 	// Check to see if we have an outstanding request and also get what the request was for if it exists.
-	blkID, ok := t.blkReqs.Remove(vdr, requestID)
-	if !ok {
+	blkID, found, retrying := t.blkReqs.Timeout(vdr, requestID, t.pickAlternateValidator)
+	if !found {
 		t.Ctx.Log.Debug("getFailed(%s, %d) called without having sent corresponding Get", vdr, requestID)
 		return nil
 	}
 
-	// Because the get request was dropped, we no longer expect blkID to be issued.
+	t.emit(TraceRequestTimedOut, blkID, ids.ID{}, vdr, requestID, nil)
+	t.updateRequestMetrics()
+
+	if retrying {
+		// The scheduler has already queued a retry against a different
+		// validator (or the same one, if none other is available) after a
+		// backoff; the block's blocked dependents keep waiting.
+		t.Ctx.Log.Debug("Get(%s, %d, %s) timed out; retrying with backoff", vdr, requestID, blkID)
+		return t.errs.Err
+	}
+
+	// Retries are exhausted. Because the get request was dropped, we no
+	// longer expect blkID to be issued.
+	t.Ctx.Log.Debug("Get(%s, %d, %s) timed out too many times; abandoning", vdr, requestID, blkID)
 	t.blocked.Abandon(blkID)
 	return t.errs.Err
 }
 
+// GetAncestorsFailed implements the Engine interface
+func (t *Transitive) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) error {
+	// not done bootstrapping --> didn't send a GetAncestors --> this message is invalid
+	if !t.Ctx.IsBootstrapped() {
+		t.Ctx.Log.Debug("dropping GetAncestorsFailed(%s, %d) due to bootstrapping")
+		return nil
+	}
+
+	blkID, ok := t.ancReqs.Remove(vdr, requestID)
+	if !ok {
+		t.Ctx.Log.Debug("getAncestorsFailed(%s, %d) called without having sent corresponding GetAncestors", vdr, requestID)
+		return nil
+	}
+
+	// The ancestors request failed, so fall back to asking [vdr] for the
+	// single block. This keeps the blocked descendants from stalling
+	// indefinitely; no other validator is asked for the full chain here.
+	t.sendRequest(vdr, blkID)
+	return t.errs.Err
+}
+
+// MultiPut implements the Engine interface
+// Assumes [blks] has no duplicates and is ordered from the requested block
+// down through its ancestors (i.e. blks[0] is the block originally asked for).
+func (t *Transitive) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte) error {
+	if !t.IsBootstrapped() {
+		t.Ctx.Log.Debug("dropping MultiPut(%s, %d, len(blks)=%d) due to bootstrapping", vdr, requestID, len(blks))
+		return nil
+	}
+
+	if _, ok := t.ancReqs.Remove(vdr, requestID); !ok {
+		t.Ctx.Log.Debug("MultiPut(%s, %d) called without having sent corresponding GetAncestors", vdr, requestID)
+		return nil
+	}
+
+	if len(blks) == 0 {
+		t.Ctx.Log.Debug("MultiPut(%s, %d) contained no blocks", vdr, requestID)
+		return nil
+	}
+
+	for _, blkBytes := range blks {
+		blk, err := t.VM.ParseBlock(blkBytes)
+		if err != nil {
+			t.Ctx.Log.Debug("failed to parse block in MultiPut(%s, %d): %s", vdr, requestID, err)
+			t.Ctx.Log.Verbo("block:\n%s", formatting.DumpBytes{Bytes: blkBytes})
+			break
+		}
+
+		blkID := blk.ID()
+		if blk.Status() == choices.Processing { // Pin this block in memory until it's decided or dropped
+			t.processing[blkID.Key()] = trackedBlock{blk: blk, source: PullReplied}
+			t.droppedCache.Evict(blkID)
+			t.numProcessing.Set(float64(len(t.processing)))
+		}
+
+		// Issue the block (and any ancestors we already have) to consensus.
+		// If this block still has missing ancestors, the next iteration of
+		// this loop, or a subsequent fetch, will fill them in.
+		if _, err := t.issueFrom(vdr, blk); err != nil {
+			return err
+		}
+	}
+	return t.errs.Err
+}
+
 // PullQuery implements the Engine interface
 func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID) error {
 	// If the engine hasn't been bootstrapped, we aren't ready to respond to queries
@@ -302,6 +540,7 @@ func (t *Transitive) PullQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID)
 	}
 
 	t.blocked.Register(c)
+	t.emit(TraceBlockReceived, blkID, ids.ID{}, vdr, requestID, nil)
 	return t.errs.Err
 }
 
@@ -323,10 +562,11 @@ func (t *Transitive) PushQuery(vdr ids.ShortID, requestID uint32, blkID ids.ID,
 		t.Ctx.Log.Debug("query said block's ID is %s but parsed ID %s. Dropping query", blkID, blk.ID())
 		return nil
 	} else if blk.Status() == choices.Processing { // Pin this block in memory until it's decided or dropped
-		t.processing[blkID.Key()] = blk
+		t.processing[blkID.Key()] = trackedBlock{blk: blk, source: Gossiped}
 		t.droppedCache.Evict(blkID)
 		t.numProcessing.Set(float64(len(t.processing)))
 	}
+	t.emit(TraceBlockReceived, blkID, blk.Parent(), vdr, requestID, nil)
 
 	// issue the block into consensus. If the block has already been issued,
 	// this will be a noop. If this block has missing dependencies, vdr will
@@ -361,6 +601,28 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) err
 
 	t.Ctx.Log.Verbo("Chits(%s, %d) contains vote for %s", vdr, requestID, blkID)
 
+	// We don't have this block yet. Request it, the same catch-up signal an
+	// honest node a few blocks behind would trigger on any other unknown
+	// block, instead of just discarding the vote.
+	if _, err := t.GetBlock(blkID); err != nil {
+		t.Ctx.Log.Debug("Chits(%s, %d) referenced unresolvable block %s; requesting it", vdr, requestID, blkID)
+		t.reportFilteredVote(vdr, requestID, blkID)
+		t.sendRequest(vdr, blkID)
+		// The router already cleared this query's timeout before Chits ran,
+		// so without this the poll for requestID could never terminate on
+		// vdr - mirrors the votes.Len() != 1 branch above.
+		return t.QueryFailed(vdr, requestID)
+	}
+
+	// A validator gets at most one counted vote per poll. A byzantine
+	// validator replaying Chits for the same poll with a different
+	// (conflicting) block is refused here rather than forwarded to the poll.
+	if !t.votes.Admit(vdr, requestID, blkID) {
+		t.Ctx.Log.Debug("dropping duplicate/conflicting Chits(%s, %d) for %s", vdr, requestID, blkID)
+		t.reportFilteredVote(vdr, requestID, blkID)
+		return nil
+	}
+
 	// Will record chits once [blkID] has been issued into consensus
 	v := &voter{
 		t:         t,
@@ -380,9 +642,94 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) err
 	}
 
 	t.blocked.Register(v)
+	t.checkEquivocation(vdr, requestID, blkID)
+	t.emit(TraceChitApplied, blkID, ids.ID{}, vdr, requestID, nil)
 	return t.errs.Err
 }
 
+// reportFilteredVote records that a vote from [vdr] in poll [requestID] was
+// dropped before reaching the poll, and surfaces it via
+// filteredVoteReporter for reputation scoring.
+func (t *Transitive) reportFilteredVote(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
+	t.numFilteredVotes++
+	if t.filteredVoteReporter != nil {
+		t.filteredVoteReporter.ReportFilteredVote(vdr, requestID, blkID)
+	}
+}
+
+// checkEquivocation records [vdr]'s vote for [blkID] in poll [requestID] and
+// reports an equivocation for every other still-tracked poll where [vdr]
+// voted for a block that isn't on the same chain as [blkID].
+func (t *Transitive) checkEquivocation(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
+	others := t.equivocations.Record(vdr, requestID, blkID)
+	for _, other := range others {
+		if other.Equals(blkID) {
+			continue
+		}
+		sameChain, proven := t.onSameChain(blkID, other)
+		if !proven || sameChain {
+			// Either the two blocks share a lineage, or we couldn't walk far
+			// enough locally (e.g. an ancestor we haven't fetched yet) to
+			// actually prove a fork. An unresolved walk is not evidence of
+			// equivocation.
+			continue
+		}
+		t.numEquivocations++
+		t.Ctx.Log.Warn("validator %s equivocated: voted for both %s and %s in concurrent polls", vdr, blkID, other)
+		if t.equivocationReporter != nil {
+			t.equivocationReporter.ReportEquivocation(vdr, blkID, other)
+		}
+		t.emit(TraceEquivocationDetected, blkID, other, vdr, requestID, nil)
+	}
+}
+
+// onSameChain returns whether one of [a]/[b] is an ancestor of the other, or
+// they're the same block, by walking back a bounded number of parents from
+// each side. The second return value is false if that couldn't actually be
+// resolved - e.g. a walk was cut short by an ancestor we haven't fetched
+// locally - rather than both walks running to completion (reaching genesis
+// or maxDepth) without finding the other block. Callers must not treat an
+// unresolved walk as proof of a fork.
+func (t *Transitive) onSameChain(a, b ids.ID) (sameChain, ok bool) {
+	if a.Equals(b) {
+		return true, true
+	}
+	const maxDepth = 256
+	// walk reports whether [target] is an ancestor of [from], and whether
+	// the walk was conclusive - false if it was cut short by a block we
+	// haven't fetched, rather than by reaching genesis or maxDepth.
+	walk := func(from, target ids.ID) (found, conclusive bool) {
+		cur := from
+		for i := 0; i < maxDepth; i++ {
+			blk, err := t.GetBlock(cur)
+			if err != nil {
+				return false, false
+			}
+			parent := blk.Parent()
+			if parent.Equals(target) {
+				return true, true
+			}
+			if parent.Equals(cur) { // genesis parents itself
+				return false, true
+			}
+			cur = parent
+		}
+		return false, true
+	}
+
+	if found, conclusive := walk(a, b); found {
+		return true, true
+	} else if !conclusive {
+		return false, false
+	}
+	if found, conclusive := walk(b, a); found {
+		return true, true
+	} else if !conclusive {
+		return false, false
+	}
+	return false, true
+}
+
 // QueryFailed implements the Engine interface
 func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
 	// If the engine hasn't been bootstrapped, we didn't issue a query
@@ -396,6 +743,7 @@ func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
 		vdr:       vdr,
 		requestID: requestID,
 	})
+	t.emit(TracePollTerminated, ids.ID{}, ids.ID{}, vdr, requestID, nil)
 	return t.errs.Err
 }
 
@@ -435,7 +783,7 @@ func (t *Transitive) Notify(msg common.Message) error {
 			t.Ctx.Log.Warn("built block with parent: %s, expected %s", parentID, pref)
 		}
 
-		t.processing[blkID.Key()] = blk
+		t.processing[blkID.Key()] = trackedBlock{blk: blk, source: LocallyBuilt}
 		t.droppedCache.Evict(blkID)
 		t.numProcessing.Set(float64(len(t.processing))) // Record metric
 		added, err := t.issueWithAncestors(blk)
@@ -515,10 +863,24 @@ func (t *Transitive) issueFrom(vdr ids.ShortID, blk snowman.Block) (bool, error)
 		}
 		blk, err = t.GetBlock(blkID)
 		if err != nil || !blk.Status().Fetched() {
-			// If we don't have this ancestor, request it from [vdr]
-			t.sendRequest(vdr, blkID)
+			// We don't know this ancestor's own parent, so we can't see how
+			// deep the missing run actually goes in a single pass. Instead,
+			// track how many times in a row this exact gap has stalled
+			// issuance of this branch: a gap that keeps recurring escalates
+			// from a single Get to a GetAncestors instead of retrying the
+			// same Get forever.
+			key := blkID.Key()
+			misses := t.ancestorMisses[key] + 1
+			t.ancestorMisses[key] = misses
+
+			if misses > ancestorsReqThreshold {
+				t.sendGetAncestors(vdr, blkID)
+			} else {
+				t.sendRequestWithPriority(vdr, blkID, misses)
+			}
 			return false, nil
 		}
+		delete(t.ancestorMisses, blkID.Key())
 	}
 	return t.Consensus.Issued(blk), nil
 }
@@ -538,7 +900,9 @@ func (t *Transitive) issueWithAncestors(blk snowman.Block) (bool, error) {
 		blkID = blk.Parent()
 		blk, err = t.GetBlock(blkID)
 		if err != nil { // Can't find the next ancestor
-			blk = &missing.Block{BlkID: blkID}
+			blk = t.trackMissingBlock(blkID)
+		} else {
+			delete(t.missingBlocks, blkID.Key())
 		}
 	}
 
@@ -547,6 +911,16 @@ func (t *Transitive) issueWithAncestors(blk snowman.Block) (bool, error) {
 		return true, nil
 	}
 
+	// This exact ancestor has been missing for longer than missingBlockTTL
+	// since it was first seen. Give up on it now instead of waiting
+	// indefinitely on its request.
+	if mb, ok := t.missingBlocks[blkID.Key()]; ok && mb.Expired(time.Now(), missingBlockTTL) {
+		delete(t.missingBlocks, blkID.Key())
+		t.blkReqs.RemoveAny(blkID)
+		t.blocked.Abandon(blkID)
+		return false, t.errs.Err
+	}
+
 	// There's an outstanding request for this block.
 	// We can just wait for that request to succeed or fail.
 	if t.blkReqs.Contains(blkID) {
@@ -559,6 +933,20 @@ func (t *Transitive) issueWithAncestors(blk snowman.Block) (bool, error) {
 	return false, t.errs.Err
 }
 
+// trackMissingBlock returns the persistent missing.Block sentinel for
+// [blkID], creating one on first sight. Keeping it persistent across calls,
+// rather than a fresh zero-state sentinel every time, lets Attempts/
+// FirstSeen actually accumulate while the block stays unresolved.
+func (t *Transitive) trackMissingBlock(blkID ids.ID) *missing.Block {
+	key := blkID.Key()
+	mb, ok := t.missingBlocks[key]
+	if !ok {
+		mb = missing.NewBlock(blkID, t.missingResolver)
+		t.missingBlocks[key] = mb
+	}
+	return mb
+}
+
 // Issue [blk] to consensus once its ancestors have been issued.
 func (t *Transitive) issue(blk snowman.Block) error {
 	blkID := blk.ID()
@@ -589,27 +977,91 @@ func (t *Transitive) issue(blk snowman.Block) error {
 	}
 
 	t.blocked.Register(i)
+	t.emit(TraceBlockQueued, blkID, parentID, ids.ShortID{}, 0, nil)
 
 	// Tracks performance statistics
-	t.numRequests.Set(float64(t.blkReqs.Len()))
+	t.updateRequestMetrics()
 	t.numBlocked.Set(float64(t.pending.Len()))
 	return t.errs.Err
 }
 
-// Request that [vdr] send us block [blkID]
+// Request that [vdr] send us block [blkID]. The request is issued
+// immediately if our per-peer and global outstanding-request budgets allow
+// it; otherwise it's queued at the highest priority (distance 0).
 func (t *Transitive) sendRequest(vdr ids.ShortID, blkID ids.ID) {
-	// There is already an outstanding request for this block
-	if t.blkReqs.Contains(blkID) {
+	t.sendRequestWithPriority(vdr, blkID, 0)
+}
+
+// sendRequestWithPriority is like sendRequest, but [distance] (the number of
+// missing ancestors between this request and the block we actually want
+// issued) determines how this request is ordered against others competing
+// for the outstanding-request budget. Lower is more urgent.
+func (t *Transitive) sendRequestWithPriority(vdr ids.ShortID, blkID ids.ID, distance int) {
+	t.blkReqs.Schedule(vdr, blkID, distance)
+	t.updateRequestMetrics()
+}
+
+// updateRequestMetrics refreshes the request-count gauge and its breakdown
+// by state (queued behind a cap, in flight on the wire, or backing off after
+// a timeout). ancReqs has no scheduler of its own, so its count is folded
+// into the in-flight bucket alongside blkReqs's. This is the one place that
+// sets numRequests, so every caller - including sendGetAncestors - agrees on
+// the blkReqs.Len()+ancReqs.Len() definition instead of disagreeing on
+// whether ancestor requests count.
+func (t *Transitive) updateRequestMetrics() {
+	t.requestsByState.WithLabelValues("queued").Set(float64(t.blkReqs.NumQueued()))
+	t.requestsByState.WithLabelValues("in_flight").Set(float64(t.blkReqs.NumInFlight() + t.ancReqs.Len()))
+	t.requestsByState.WithLabelValues("backing_off").Set(float64(t.blkReqs.NumBackingOff()))
+	t.numRequests.Set(float64(t.blkReqs.Len() + t.ancReqs.Len()))
+}
+
+// dispatchGet actually puts a Get on the wire; it's invoked by [t.blkReqs]
+// once budget allows, either immediately or after draining the queue.
+func (t *Transitive) dispatchGet(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
+	t.Ctx.Log.Verbo("sending Get(%s, %d, %s)", vdr, requestID, blkID)
+	if mb, ok := t.missingBlocks[blkID.Key()]; ok {
+		mb.RecordAttempt(vdr)
+	}
+	t.Sender.Get(vdr, requestID, blkID)
+	t.emit(TraceGetSent, blkID, ids.ID{}, vdr, requestID, nil)
+}
+
+// Request that [vdr] send us the ancestry of block [blkID] via MultiPut
+func (t *Transitive) sendGetAncestors(vdr ids.ShortID, blkID ids.ID) {
+	// There is already an outstanding request for this block's ancestry
+	if t.ancReqs.Contains(blkID) {
 		return
 	}
+	// A single outstanding Get for this exact block is how we got here in
+	// the first place (issueFrom only escalates once the same gap has
+	// stalled more than once), so it supersedes that Get rather than
+	// deferring to it - otherwise escalation could never actually happen,
+	// since the Get it's deferring to is never cleared except by this call.
+	t.blkReqs.RemoveAny(blkID)
 
 	t.RequestID++
-	t.blkReqs.Add(vdr, t.RequestID, blkID)
-	t.Ctx.Log.Verbo("sending Get(%s, %d, %s)", vdr, t.RequestID, blkID)
-	t.Sender.Get(vdr, t.RequestID, blkID)
+	t.ancReqs.Add(vdr, t.RequestID, blkID)
+	t.Ctx.Log.Verbo("sending GetAncestors(%s, %d, %s)", vdr, t.RequestID, blkID)
+	t.Sender.GetAncestors(vdr, t.RequestID, blkID)
+	t.emit(TraceAncestorsRequest, blkID, ids.ID{}, vdr, t.RequestID, nil)
 
-	// Tracks performance statistics
-	t.numRequests.Set(float64(t.blkReqs.Len()))
+	t.updateRequestMetrics()
+}
+
+// pickAlternateValidator samples a validator other than [exclude] to retry a
+// timed-out block request against. Returns false if no alternate is
+// available, e.g. because the validator set only has one member.
+func (t *Transitive) pickAlternateValidator(exclude ids.ShortID) (ids.ShortID, bool) {
+	for attempt := 0; attempt < 2; attempt++ {
+		vdrs, err := t.Validators.Sample(1)
+		if err != nil || len(vdrs) == 0 {
+			return ids.ShortID{}, false
+		}
+		if candidate := vdrs[0].ID(); !candidate.Equals(exclude) {
+			return candidate, true
+		}
+	}
+	return ids.ShortID{}, false
 }
 
 // send a pull request for this block ID
@@ -628,6 +1080,7 @@ func (t *Transitive) pullSample(blkID ids.ID) {
 		vdrSet.Add(vdrBag.List()...)
 
 		t.Sender.PullQuery(vdrSet, t.RequestID, blkID)
+		t.emit(TracePollStarted, blkID, ids.ID{}, ids.ShortID{}, t.RequestID, nil)
 	} else if err != nil {
 		t.Ctx.Log.Error("query for %s was dropped due to an insufficient number of validators", blkID)
 	}
@@ -635,6 +1088,11 @@ func (t *Transitive) pullSample(blkID ids.ID) {
 
 // send a push request for this block
 func (t *Transitive) pushSample(blk snowman.Block) {
+	// Blocks we only observed while bootstrapping have already been decided
+	// by the network; there's no preference left to poll for.
+	if t.blockSource(blk.ID()) == Bootstrap {
+		return
+	}
 	t.Ctx.Log.Verbo("about to sample from: %s", t.Validators)
 	vdrs, err := t.Validators.Sample(t.Params.K)
 	vdrBag := ids.ShortBag{}
@@ -647,8 +1105,55 @@ func (t *Transitive) pushSample(blk snowman.Block) {
 		vdrSet := ids.ShortSet{}
 		vdrSet.Add(vdrBag.List()...)
 		t.Sender.PushQuery(vdrSet, t.RequestID, blk.ID(), blk.Bytes())
+		t.emit(TracePollStarted, blk.ID(), ids.ID{}, ids.ShortID{}, t.RequestID, nil)
 	} else if err != nil {
 		t.Ctx.Log.Error("query for %s was dropped due to an insufficient number of validators", blk.ID())
+
+		// This block can't even get a poll started, so it has no way to
+		// accumulate alpha votes on its own. Don't wait for the next natural
+		// repoll to notice: walk its descendants now and re-request anything
+		// missing in between, so the subtree isn't stuck behind a query that
+		// will never fire.
+		t.triggerFalterRecovery(blk.ID())
+	}
+}
+
+// maxFalterWalkDepth bounds how far triggerFalterRecovery walks back from a
+// pending block while looking for a missing ancestor.
+const maxFalterWalkDepth = 32
+
+// triggerFalterRecovery proactively re-requests any missing block between
+// [blkID] - a block whose push query couldn't even be started for lack of
+// sampleable validators - and the blocks in t.pending that descend from it.
+// Those descendants are already blocked waiting for blkID's branch to be
+// issued; if the actual gap is a block further down that we never received
+// (or whose request expired without a retry), this closes it without
+// waiting for the next repoll.
+func (t *Transitive) triggerFalterRecovery(blkID ids.ID) {
+	for _, pendingID := range t.pending.List() {
+		cur := pendingID
+		for depth := 0; depth < maxFalterWalkDepth; depth++ {
+			if cur.Equals(blkID) {
+				break // this descendant's path back to the faltering block is intact
+			}
+			blk, err := t.GetBlock(cur)
+			if err != nil {
+				// Found the gap. Re-request it if nothing is currently
+				// outstanding, so an expired/dropped request doesn't wedge
+				// this entire subtree until the next repoll.
+				if !t.blkReqs.Contains(cur) {
+					if vdr, ok := t.pickAlternateValidator(ids.ShortID{}); ok {
+						t.sendRequestWithPriority(vdr, cur, depth)
+					}
+				}
+				break
+			}
+			parent := blk.Parent()
+			if parent.Equals(cur) { // genesis parents itself
+				break
+			}
+			cur = parent
+		}
 	}
 }
 
@@ -664,12 +1169,17 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 
 	// Make sure this block is valid
 	if err := blk.Verify(); err != nil {
+		source := Bootstrap
+		if tracked, ok := t.processing[blkID.Key()]; ok {
+			source = tracked.source
+		}
 		delete(t.processing, blkID.Key()) // Unpin from memory
-		t.droppedCache.Put(blkID, blk)
+		t.droppedCache.Put(blkID, trackedBlock{blk: blk, source: source})
 		// if verify fails, then all descendants are also invalid
 		t.blocked.Abandon(blkID)
 		t.numBlocked.Set(float64(t.pending.Len())) // Tracks performance statistics
 		t.numProcessing.Set(float64(len(t.processing)))
+		t.emit(TraceBlockDropped, blkID, blk.Parent(), ids.ShortID{}, 0, err)
 		return t.errs.Err
 	}
 
@@ -681,6 +1191,10 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 		t.droppedCache.Evict(blkID)       // Remove from dropped cache, if it was in there
 		delete(t.processing, blkID.Key()) // This block was rejected. Unpin from memory.
 		t.numProcessing.Set(float64(len(t.processing)))
+		t.emit(TraceBlockRejected, blkID, blk.Parent(), ids.ShortID{}, 0, nil)
+	} else {
+		t.recordIssued(t.blockSource(blkID))
+		t.emit(TraceBlockIssued, blkID, blk.Parent(), ids.ShortID{}, 0, nil)
 	}
 
 	// Add all the oracle blocks if they exist. We call verify on all the blocks
@@ -735,11 +1249,47 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 	t.repoll()
 
 	// Tracks performance statistics
-	t.numRequests.Set(float64(t.blkReqs.Len()))
+	t.updateRequestMetrics()
 	t.numBlocked.Set(float64(t.pending.Len()))
 	return t.errs.Err
 }
 
+// emit sends a structured trace record to the configured ConsensusTracer.
+// [blkID] and [vdr] are optional; pass ids.Empty/ids.ShortEmpty when the
+// event has no associated block or validator.
+func (t *Transitive) emit(event TraceEvent, blkID, parentID ids.ID, vdr ids.ShortID, requestID uint32, err error) {
+	rec := TraceRecord{
+		Seq:        nextTraceSeq(),
+		WallTime:   time.Now(),
+		Event:      event,
+		BlkID:      blkID,
+		ParentID:   parentID,
+		VdrID:      vdr,
+		RequestID:  requestID,
+		Preference: t.Consensus.Preference(),
+		Processing: len(t.processing),
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	t.tracer.Trace(rec)
+}
+
+// blockSource returns the BlockSource recorded for [id] in processing, or
+// Bootstrap if it's not currently pinned (e.g. it was delivered during
+// startup and never went through Put/PushQuery/Notify).
+func (t *Transitive) blockSource(id ids.ID) BlockSource {
+	if tracked, ok := t.processing[id.Key()]; ok {
+		return tracked.source
+	}
+	return Bootstrap
+}
+
+// recordIssued bumps the per-source count of blocks issued to consensus.
+func (t *Transitive) recordIssued(source BlockSource) {
+	t.blocksIssuedBySource.WithLabelValues(source.String()).Inc()
+}
+
 // IsBootstrapped returns true iff this chain is done bootstrapping
 func (t *Transitive) IsBootstrapped() bool {
 	return t.Ctx.IsBootstrapped()
@@ -749,11 +1299,11 @@ func (t *Transitive) IsBootstrapped() bool {
 func (t *Transitive) GetBlock(id ids.ID) (snowman.Block, error) {
 	// Check the processing set
 	if block, ok := t.processing[id.Key()]; ok {
-		return block, nil
+		return block.blk, nil
 	}
 	// Check the cache of recently dropped blocks
 	if block, ok := t.droppedCache.Get(id); ok {
-		return block.(snowman.Block), nil
+		return block.(trackedBlock).blk, nil
 	}
 	// Not processing. Check the database.
 	return t.VM.GetBlock(id)
@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import "github.com/ava-labs/gecko/ids"
+
+// maxTrackedPolls bounds the memory a voteFilter uses. Nothing tells the
+// filter when a poll has fully resolved, so the oldest poll is evicted once
+// this many are tracked at once rather than waiting on an explicit signal.
+const maxTrackedPolls = 256
+
+// FilteredVoteReporter is notified whenever a vote is dropped by the
+// voteFilter, either because a validator tried to cast more than one vote in
+// a single poll or because the vote named a block we can't resolve. It's a
+// hook rather than a hard failure: the poll just proceeds without the vote,
+// but operators and reputation scoring need to know a validator misbehaved.
+type FilteredVoteReporter interface {
+	ReportFilteredVote(vdr ids.ShortID, requestID uint32, blkID ids.ID)
+}
+
+// voteFilter collapses each validator to at most one counted vote per poll.
+// A byzantine validator can respond to a single query with votes for
+// multiple conflicting descendants (e.g. two sibling blocks); without this,
+// every one of those votes would be forwarded to the poll and could count
+// more than once toward alpha.
+type voteFilter struct {
+	order []uint32 // requestIDs in arrival order, oldest first
+
+	// Key: requestID of an outstanding poll
+	// Value: validators who have already had a vote counted in that poll
+	counted map[uint32]map[[20]byte]ids.ID
+}
+
+func newVoteFilter() *voteFilter {
+	return &voteFilter{counted: make(map[uint32]map[[20]byte]ids.ID)}
+}
+
+// Admit returns true iff this is the first vote [vdr] has cast in poll
+// [requestID]. Later calls for the same (requestID, vdr) pair - whether a
+// replay of the same vote or a conflicting one - are refused so a validator
+// is never counted more than once toward a single poll's alpha threshold.
+func (f *voteFilter) Admit(vdr ids.ShortID, requestID uint32, blkID ids.ID) bool {
+	byVdr, ok := f.counted[requestID]
+	if !ok {
+		byVdr = make(map[[20]byte]ids.ID)
+		f.counted[requestID] = byVdr
+		f.order = append(f.order, requestID)
+	}
+	key := vdr.Key()
+	if _, voted := byVdr[key]; voted {
+		return false
+	}
+	byVdr[key] = blkID
+
+	for len(f.order) > maxTrackedPolls {
+		oldest := f.order[0]
+		f.order = f.order[1:]
+		delete(f.counted, oldest)
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import "testing"
+
+// TestVoteFilterRejectsSiblingVotes covers the byzantine scenario this
+// filter exists for: a validator responds to a single query with votes for
+// two conflicting sibling blocks. Only the first should be admitted, so a
+// double vote can't count twice toward a poll's alpha threshold.
+func TestVoteFilterRejectsSiblingVotes(t *testing.T) {
+	f := newVoteFilter()
+	vdr := testShortID(1)
+	blkA, blkB := testID(1), testID(2)
+
+	if !f.Admit(vdr, 1, blkA) {
+		t.Fatalf("expected the validator's first vote in the poll to be admitted")
+	}
+	if f.Admit(vdr, 1, blkB) {
+		t.Fatalf("expected a second, conflicting vote from the same validator in the same poll to be rejected")
+	}
+	if f.Admit(vdr, 1, blkA) {
+		t.Fatalf("expected a replay of the same vote to also be rejected")
+	}
+}
+
+func TestVoteFilterAdmitsDifferentValidatorsAndPolls(t *testing.T) {
+	f := newVoteFilter()
+	vdrA, vdrB := testShortID(1), testShortID(2)
+	blk := testID(1)
+
+	if !f.Admit(vdrA, 1, blk) {
+		t.Fatalf("expected vdrA's vote to be admitted")
+	}
+	if !f.Admit(vdrB, 1, blk) {
+		t.Fatalf("expected a different validator's vote in the same poll to be admitted")
+	}
+	if !f.Admit(vdrA, 2, blk) {
+		t.Fatalf("expected vdrA's vote in a different poll to be admitted")
+	}
+}
+
+func TestVoteFilterEvictsOldestPoll(t *testing.T) {
+	f := newVoteFilter()
+	vdr := testShortID(1)
+
+	for i := uint32(0); i < maxTrackedPolls+2; i++ {
+		f.Admit(vdr, i, testID(1))
+	}
+
+	// The oldest poll (requestID 0) should have been evicted, so the same
+	// validator can vote in it again as if it were brand new.
+	if !f.Admit(vdr, 0, testID(1)) {
+		t.Fatalf("expected the evicted poll's tracking to have been forgotten")
+	}
+}
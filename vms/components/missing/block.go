@@ -4,18 +4,56 @@
 package missing
 
 import (
-	"errors"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 )
 
-var (
-	errMissingBlock = errors.New("missing block")
-)
+// Block represents a block that can't be found. Beyond the sentinel ID,
+// it doubles as the retry state for that fetch: FirstSeen/Attempts/Requested
+// let the bootstrapper and fetch layer tell "never asked for this" apart
+// from "asked many peers over a long time with no answer" so they can
+// prioritize retries and eventually give up.
+type Block struct {
+	BlkID ids.ID
+
+	// FirstSeen is when this block was first represented as missing.
+	FirstSeen time.Time
+	// Requested is when the most recent fetch attempt was issued.
+	Requested time.Time
+	// Attempts counts how many fetch attempts have been made for this block.
+	Attempts uint32
+	// LastPeer is the validator the most recent fetch attempt was sent to.
+	LastPeer ids.ShortID
+
+	// Resolver governs how Accept/Reject/Verify react to being called on
+	// this placeholder. A nil Resolver falls back to DefaultResolver.
+	Resolver *Resolver
+}
+
+// NewBlock returns a missing-block sentinel for [blkID], stamped with the
+// current time as its FirstSeen. [resolver] governs how Accept/Reject/Verify
+// react if called on the sentinel; pass nil to use DefaultResolver.
+func NewBlock(blkID ids.ID, resolver *Resolver) *Block {
+	return &Block{BlkID: blkID, FirstSeen: time.Now(), Resolver: resolver}
+}
+
+// RecordAttempt notes that a fetch for this block was just sent to [peer].
+func (mb *Block) RecordAttempt(peer ids.ShortID) {
+	mb.Requested = time.Now()
+	mb.LastPeer = peer
+	mb.Attempts++
+}
 
-// Block represents a block that can't be found
-type Block struct{ BlkID ids.ID }
+// Expired returns true if this block has been missing, without being
+// fetched, for longer than [ttl].
+func (mb *Block) Expired(now time.Time, ttl time.Duration) bool {
+	if mb.FirstSeen.IsZero() {
+		return false
+	}
+	return now.Sub(mb.FirstSeen) > ttl
+}
 
 // ID ...
 func (mb *Block) ID() ids.ID { return mb.BlkID }
@@ -24,10 +62,10 @@ func (mb *Block) ID() ids.ID { return mb.BlkID }
 func (mb *Block) Height() uint64 { return 0 }
 
 // Accept ...
-func (*Block) Accept() error { return errMissingBlock }
+func (mb *Block) Accept() error { return mb.Resolver.resolve(ErrAcceptMissing) }
 
 // Reject ...
-func (*Block) Reject() error { return errMissingBlock }
+func (mb *Block) Reject() error { return mb.Resolver.resolve(ErrRejectMissing) }
 
 // Status ...
 func (*Block) Status() choices.Status { return choices.Unknown }
@@ -36,7 +74,7 @@ func (*Block) Status() choices.Status { return choices.Unknown }
 func (*Block) Parent() ids.ID { return ids.ID{} }
 
 // Verify ...
-func (*Block) Verify() error { return errMissingBlock }
+func (mb *Block) Verify() error { return mb.Resolver.resolve(ErrVerifyMissing) }
 
 // Bytes ...
 func (*Block) Bytes() []byte { return nil }
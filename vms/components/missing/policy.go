@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package missing
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// Policy controls how a missing placeholder (Block, Vertex, Tx) reacts when
+// code calls Accept/Reject/Verify on it, i.e. treats a sentinel for
+// something we never actually fetched as if it were the real thing.
+type Policy int
+
+const (
+	// PolicyError returns the typed sentinel error. This is the default,
+	// for backward compatibility with callers that check the returned
+	// error rather than expecting a panic.
+	PolicyError Policy = iota
+	// PolicyPanic panics instead of returning an error, so a bug that lets
+	// a missing placeholder reach Accept/Reject/Verify is caught
+	// immediately. Intended for development builds.
+	PolicyPanic
+	// PolicyLog logs the violation via the Resolver's Logger, in addition
+	// to returning the typed sentinel error. Intended for production
+	// builds that want visibility without crashing.
+	PolicyLog
+)
+
+var (
+	// ErrAcceptMissing is returned (or panicked with, under PolicyPanic)
+	// when Accept is called on a missing placeholder.
+	ErrAcceptMissing = errors.New("cannot accept a missing block/vertex/tx")
+	// ErrRejectMissing is returned (or panicked with, under PolicyPanic)
+	// when Reject is called on a missing placeholder.
+	ErrRejectMissing = errors.New("cannot reject a missing block/vertex/tx")
+	// ErrVerifyMissing is returned (or panicked with, under PolicyPanic)
+	// when Verify is called on a missing placeholder.
+	ErrVerifyMissing = errors.New("cannot verify a missing block/vertex/tx")
+)
+
+// Resolver applies a Policy to a missing-placeholder violation. It's owned
+// by the engine/VM instance that constructs the placeholder rather than
+// kept as package state: a single avalanchego process runs many chains
+// concurrently, and one chain opting into PolicyPanic for a dev build must
+// not make every other chain sharing this package panic too.
+type Resolver struct {
+	Policy Policy
+	Logger logging.Logger
+}
+
+// DefaultResolver is used by placeholders constructed without an explicit
+// Resolver (e.g. a bare struct literal). It resolves under PolicyError,
+// matching this package's long-standing default behavior.
+var DefaultResolver = &Resolver{Policy: PolicyError}
+
+// resolve applies [r]'s policy to the typed sentinel error [err] for the
+// violation that just happened. A nil Resolver falls back to
+// DefaultResolver.
+func (r *Resolver) resolve(err error) error {
+	if r == nil {
+		r = DefaultResolver
+	}
+	switch r.Policy {
+	case PolicyPanic:
+		panic(err)
+	case PolicyLog:
+		if r.Logger != nil {
+			r.Logger.Warn("%s", err)
+		}
+	}
+	return err
+}
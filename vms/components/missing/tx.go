@@ -0,0 +1,74 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package missing
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+)
+
+// Tx represents a transaction that can't be found. It lets a tx job queue
+// enqueue a dependency fetch for [TxID] the same way it would for any other
+// unresolved transaction, instead of the engine special-casing a nil GetTx
+// result.
+type Tx struct {
+	TxID ids.ID
+
+	// Resolver governs how Accept/Reject/Verify react to being called on
+	// this placeholder. A nil Resolver falls back to DefaultResolver.
+	Resolver *Resolver
+}
+
+// NewTx returns a missing-tx sentinel for [txID]. [resolver] governs how
+// Accept/Reject/Verify react if called on the sentinel; pass nil to use
+// DefaultResolver.
+func NewTx(txID ids.ID, resolver *Resolver) *Tx {
+	return &Tx{TxID: txID, Resolver: resolver}
+}
+
+// ID ...
+func (mt *Tx) ID() ids.ID { return mt.TxID }
+
+// Accept ...
+func (mt *Tx) Accept() error { return mt.Resolver.resolve(ErrAcceptMissing) }
+
+// Reject ...
+func (mt *Tx) Reject() error { return mt.Resolver.resolve(ErrRejectMissing) }
+
+// Status ...
+func (*Tx) Status() choices.Status { return choices.Unknown }
+
+// Verify ...
+func (mt *Tx) Verify() error { return mt.Resolver.resolve(ErrVerifyMissing) }
+
+// Dependencies returns no dependencies, since a missing tx's real
+// dependencies aren't known until it's actually fetched.
+func (*Tx) Dependencies() []snowstorm.Tx { return nil }
+
+// InputIDs returns no inputs, since a missing tx's real inputs aren't known
+// until it's actually fetched.
+func (*Tx) InputIDs() ids.Set { return ids.Set{} }
+
+// Bytes ...
+func (*Tx) Bytes() []byte { return nil }
+
+// ParseOrMissingTx parses [txBytes] via [parse], the VM's ParseTx. If
+// parsing fails because the referenced tx isn't known yet (rather than the
+// bytes being malformed), the caller should construct [txBytes] as the
+// dependency's ID bytes and treat any parse error as "not found"; this
+// returns a missing.Tx sentinel for [txID] instead, so a tx job queue can
+// enqueue a dependency fetch for it the same way it would for any other
+// unresolved transaction feeding into snowstorm's conflict tracking.
+//
+// This tree has no avalanche bootstrap tx-job parser to call this from yet;
+// it's provided here so that parser can call it once it exists, rather than
+// duplicating the parse-or-missing fallback at every call site.
+func ParseOrMissingTx(txID ids.ID, txBytes []byte, parse func([]byte) (snowstorm.Tx, error), resolver *Resolver) (snowstorm.Tx, error) {
+	tx, err := parse(txBytes)
+	if err != nil {
+		return NewTx(txID, resolver), nil
+	}
+	return tx, nil
+}